@@ -0,0 +1,197 @@
+package go_sd_jwt
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Issuer builds a new SD-JWT, selecting which claims are disclosed in plaintext
+// and which are hidden behind a digest in the `_sd` array until a holder
+// chooses to disclose them. Create one with NewIssuer and finish with Sign.
+type Issuer struct {
+	signer Signer
+	head   map[string]any
+	err    error
+
+	alwaysVisible map[string]any
+	sd            []any
+	disclosures   []Disclosure
+}
+
+// NewIssuer creates an Issuer that will sign the resulting SD-JWT with signer.
+func NewIssuer(signer Signer) *Issuer {
+	i := &Issuer{
+		signer:        signer,
+		alwaysVisible: map[string]any{},
+	}
+	if signer == nil {
+		i.err = errNoSigner
+		return i
+	}
+	i.head = map[string]any{"alg": signer.Algorithm(), "typ": "vc+sd-jwt"}
+	return i
+}
+
+// AddAlwaysVisible adds a claim to the plaintext body of the SD-JWT. It is
+// always visible to anyone who receives the SD-JWT, regardless of what the
+// holder chooses to present.
+func (i *Issuer) AddAlwaysVisible(claim string, value any) *Issuer {
+	i.alwaysVisible[claim] = value
+	return i
+}
+
+// AddSelectivelyDisclosable adds a top level claim that is only visible once
+// the holder discloses it. A Disclosure is created for the claim and its
+// digest is added to the `_sd` array of the signed body.
+func (i *Issuer) AddSelectivelyDisclosable(claim string, value any) *Issuer {
+	disclosure, digest, err := i.newDisclosure(Pointer(claim), value)
+	if err != nil {
+		i.err = err
+		return i
+	}
+	i.disclosures = append(i.disclosures, *disclosure)
+	i.sd = append(i.sd, digest)
+	return i
+}
+
+// AddArrayElementDisclosure appends a selectively disclosable element to an
+// array claim in the plaintext body. The element is replaced in the array
+// with `{"...": "<digest>"}` per the SD-JWT array disclosure format, and a
+// matching Disclosure is produced for the holder to present later.
+func (i *Issuer) AddArrayElementDisclosure(claim string, value any) *Issuer {
+	disclosure, digest, err := i.newDisclosure(nil, value)
+	if err != nil {
+		i.err = err
+		return i
+	}
+	i.disclosures = append(i.disclosures, *disclosure)
+
+	arr, _ := i.alwaysVisible[claim].([]any)
+	arr = append(arr, arrayDisclosure{Digest: Pointer(digest)})
+	i.alwaysVisible[claim] = arr
+	return i
+}
+
+// AddDecoyDigests adds n randomly salted digests to the `_sd` array that do
+// not correspond to any real disclosure. Decoy digests are indistinguishable
+// from real ones to a verifier and are used to obscure how many claims a
+// credential actually carries.
+func (i *Issuer) AddDecoyDigests(n int) *Issuer {
+	for j := 0; j < n; j++ {
+		salt, err := newSalt()
+		if err != nil {
+			i.err = err
+			return i
+		}
+		i.sd = append(i.sd, digestOf(salt))
+	}
+	return i
+}
+
+// Sign finalizes the SD-JWT: it assembles the plaintext body (always visible
+// claims plus the `_sd` digest array), signs it with the Issuer's Signer, and
+// returns the compact `<jwt>~<disclosure1>~...~` serialization along with the
+// Disclosures the holder can later choose to present.
+func (i *Issuer) Sign() (string, []Disclosure, error) {
+	if i.err != nil {
+		return "", nil, i.err
+	}
+
+	body := map[string]any{}
+	for k, v := range i.alwaysVisible {
+		body[k] = v
+	}
+	if len(i.sd) > 0 {
+		body["_sd"] = i.sd
+		body["_sd_alg"] = "sha-256"
+	}
+
+	headBytes, err := json.Marshal(i.head)
+	if err != nil {
+		return "", nil, err
+	}
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return "", nil, err
+	}
+
+	encodedHead := base64.RawURLEncoding.EncodeToString(headBytes)
+	encodedBody := base64.RawURLEncoding.EncodeToString(bodyBytes)
+	signingInput := encodedHead + "." + encodedBody
+
+	sig, err := i.signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(sig) + "~"
+	for _, d := range i.disclosures {
+		token += d.EncodedValue() + "~"
+	}
+
+	return token, i.disclosures, nil
+}
+
+// newDisclosure creates a Disclosure for value, optionally named claimName
+// (nil for array element disclosures), and returns it alongside the base64url
+// digest of its encoded form.
+func (i *Issuer) newDisclosure(claimName *string, value any) (*Disclosure, string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return nil, "", err
+	}
+
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var rawParts []json.RawMessage
+	rawParts = append(rawParts, mustRaw(salt))
+	if claimName != nil {
+		rawParts = append(rawParts, mustRaw(*claimName))
+	}
+	rawParts = append(rawParts, valueBytes)
+
+	rawValue, err := json.Marshal(rawParts)
+	if err != nil {
+		return nil, "", err
+	}
+	encodedValue := base64.RawURLEncoding.EncodeToString(rawValue)
+
+	disclosure := &Disclosure{
+		salt:           salt,
+		claimName:      claimName,
+		claimValueJSON: valueBytes,
+		rawValue:       string(rawValue),
+		encodedValue:   encodedValue,
+	}
+
+	return disclosure, digestOf(encodedValue), nil
+}
+
+// newSalt returns a cryptographically random, base64url-encoded salt.
+func newSalt() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// digestOf returns the base64url-encoded sha-256 digest of an encoded
+// disclosure, as used in the `_sd` array and array element disclosures.
+func digestOf(encodedValue string) string {
+	h := sha256.Sum256([]byte(encodedValue))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+func mustRaw(s string) json.RawMessage {
+	b, _ := json.Marshal(s)
+	return b
+}
+
+var errNoSigner = errors.New("no signer provided to NewIssuer")
@@ -0,0 +1,139 @@
+package go_sd_jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+)
+
+// TestIssuerRoundTripWithDecoys builds an SD-JWT with the Issuer, parses it
+// back with New, and checks that GetDisclosedClaims resolves a top-level
+// selectively disclosable claim and an array element disclosure, ignoring
+// decoy digests that have no matching disclosure.
+func TestIssuerRoundTripWithDecoys(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	issuer := NewIssuer(&EdDSASigner{PrivateKey: priv}).
+		AddAlwaysVisible("iss", "https://example.com").
+		AddSelectivelyDisclosable("given_name", "Alice").
+		AddArrayElementDisclosure("nicknames", "Bobby").
+		AddDecoyDigests(2)
+
+	token, disclosures, err := issuer.Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if len(disclosures) != 2 {
+		t.Fatalf("expected 2 real disclosures, got %d", len(disclosures))
+	}
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	claims, err := sdJwt.GetDisclosedClaims()
+	if err != nil {
+		t.Fatalf("failed to resolve disclosed claims: %v", err)
+	}
+
+	if claims["iss"] != "https://example.com" {
+		t.Errorf("expected always-visible iss to be present, got %v", claims["iss"])
+	}
+	if claims["given_name"] != "Alice" {
+		t.Errorf("expected given_name to be disclosed, got %v", claims["given_name"])
+	}
+
+	nicknames, ok := claims["nicknames"].([]any)
+	if !ok || len(nicknames) != 1 || nicknames[0] != "Bobby" {
+		t.Errorf("expected nicknames to resolve to [Bobby], got %v", claims["nicknames"])
+	}
+}
+
+// TestAddAlwaysVisibleOverwrite checks that adding the same claim name twice
+// keeps only the most recent value, matching plain map assignment semantics.
+func TestAddAlwaysVisibleOverwrite(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	token, _, err := NewIssuer(&EdDSASigner{PrivateKey: priv}).
+		AddAlwaysVisible("iss", "https://first.example").
+		AddAlwaysVisible("iss", "https://second.example").
+		Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+	if (*sdJwt.Body())["iss"] != "https://second.example" {
+		t.Errorf("expected the later AddAlwaysVisible call to win, got %v", (*sdJwt.Body())["iss"])
+	}
+}
+
+// TestAddDecoyDigestsNoCollision checks that decoy digests never collide
+// with a real disclosure's digest, which would let a verifier mistake a
+// decoy for a genuine disclosure.
+func TestAddDecoyDigestsNoCollision(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	issuer := NewIssuer(&EdDSASigner{PrivateKey: priv}).
+		AddSelectivelyDisclosable("given_name", "Alice").
+		AddDecoyDigests(50)
+
+	token, disclosures, err := issuer.Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	if len(disclosures) != 1 {
+		t.Fatalf("expected 1 real disclosure, got %d", len(disclosures))
+	}
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	realDigest := digestOf(disclosures[0].EncodedValue())
+	sd, _ := (*sdJwt.Body())["_sd"].([]any)
+	if len(sd) != 51 {
+		t.Fatalf("expected 50 decoys plus 1 real digest in _sd, got %d", len(sd))
+	}
+
+	seen := map[string]bool{}
+	var realCount int
+	for _, d := range sd {
+		digest, _ := d.(string)
+		if seen[digest] {
+			t.Fatalf("duplicate digest found in _sd: %s", digest)
+		}
+		seen[digest] = true
+		if digest == realDigest {
+			realCount++
+		}
+	}
+	if realCount != 1 {
+		t.Fatalf("expected the real digest to appear exactly once, got %d", realCount)
+	}
+}
+
+// TestIssuerSignPropagatesNoSignerError checks that NewIssuer(nil) defers
+// its error until Sign rather than panicking on first use.
+func TestIssuerSignPropagatesNoSignerError(t *testing.T) {
+	issuer := NewIssuer(nil).AddAlwaysVisible("iss", "https://example.com")
+
+	_, _, err := issuer.Sign()
+	if err == nil {
+		t.Fatal("expected Sign to return an error for a nil Signer")
+	}
+}
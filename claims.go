@@ -0,0 +1,131 @@
+package go_sd_jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DecodeClaims materializes this SD-JWT's disclosed claims and unmarshals
+// them into v, in the same way json.Unmarshal would for a plain JWT body.
+// v should be a pointer, typically to a RegisteredClaims or an embedding
+// struct with custom claims.
+func (s *SdJwt) DecodeClaims(v any) error {
+	claims, err := s.GetDisclosedClaims()
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// NumericDate represents a JWT NumericDate as defined by RFC 7519 section 2:
+// seconds since the Unix epoch, encoded as a JSON number.
+type NumericDate struct {
+	time.Time
+}
+
+// UnmarshalJSON decodes a NumericDate from a JSON number of seconds since the epoch.
+func (n *NumericDate) UnmarshalJSON(b []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(b, &seconds); err != nil {
+		return err
+	}
+	n.Time = time.Unix(int64(seconds), 0)
+	return nil
+}
+
+// MarshalJSON encodes a NumericDate as a JSON number of seconds since the epoch.
+func (n NumericDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Time.Unix())
+}
+
+// Audience represents a JWT "aud" claim, which per RFC 7519 section 4.1.3 may
+// be encoded as either a single string or an array of strings.
+type Audience []string
+
+// UnmarshalJSON decodes an Audience from either a JSON string or a JSON array of strings.
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// MarshalJSON encodes an Audience as a single JSON string when it holds
+// exactly one value, and as a JSON array otherwise.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// Contains reports whether aud is present in the Audience.
+func (a Audience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// RegisteredClaims holds the registered JWT claim names defined by RFC 7519
+// section 4.1, plus the "cnf" confirmation claim used for Key Binding.
+type RegisteredClaims struct {
+	Issuer         string         `json:"iss,omitempty"`
+	Subject        string         `json:"sub,omitempty"`
+	Audience       Audience       `json:"aud,omitempty"`
+	ExpirationTime *NumericDate   `json:"exp,omitempty"`
+	NotBefore      *NumericDate   `json:"nbf,omitempty"`
+	IssuedAt       *NumericDate   `json:"iat,omitempty"`
+	ID             string         `json:"jti,omitempty"`
+	Cnf            map[string]any `json:"cnf,omitempty"`
+}
+
+// ValidateStandardClaims decodes this SD-JWT's registered claims and checks
+// exp/nbf/iat against now (allowing leeway for clock skew), and iss/aud
+// against the expected values. An empty expectedAudience or expectedIssuer
+// skips that particular check.
+func (s *SdJwt) ValidateStandardClaims(now time.Time, expectedAudience string, expectedIssuer string, leeway time.Duration) error {
+	var claims RegisteredClaims
+	if err := s.DecodeClaims(&claims); err != nil {
+		return err
+	}
+
+	if expectedIssuer != "" && claims.Issuer != expectedIssuer {
+		return errors.New("unexpected issuer")
+	}
+
+	if expectedAudience != "" && !claims.Audience.Contains(expectedAudience) {
+		return errors.New("expected audience not found")
+	}
+
+	if claims.ExpirationTime != nil && now.After(claims.ExpirationTime.Time.Add(leeway)) {
+		return errors.New("token has expired")
+	}
+
+	if claims.NotBefore != nil && now.Before(claims.NotBefore.Time.Add(-leeway)) {
+		return errors.New("token is not yet valid")
+	}
+
+	if claims.IssuedAt != nil && now.Before(claims.IssuedAt.Time.Add(-leeway)) {
+		return errors.New("token was issued in the future")
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package go_sd_jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAudienceUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Audience
+	}{
+		{"single string", `"verifier"`, Audience{"verifier"}},
+		{"array of strings", `["verifier","other"]`, Audience{"verifier", "other"}},
+		{"empty array", `[]`, Audience{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Audience
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestAudienceMarshalJSON(t *testing.T) {
+	single, err := json.Marshal(Audience{"verifier"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(single) != `"verifier"` {
+		t.Errorf("expected a bare string for a single-element Audience, got %s", single)
+	}
+
+	many, err := json.Marshal(Audience{"verifier", "other"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(many) != `["verifier","other"]` {
+		t.Errorf("expected a JSON array for a multi-element Audience, got %s", many)
+	}
+}
+
+func TestAudienceContains(t *testing.T) {
+	aud := Audience{"a", "b"}
+	if !aud.Contains("a") {
+		t.Errorf("expected Contains(a) to be true")
+	}
+	if aud.Contains("c") {
+		t.Errorf("expected Contains(c) to be false")
+	}
+}
+
+func TestNumericDateRoundTrip(t *testing.T) {
+	n := NumericDate{time.Unix(1516239022, 0)}
+
+	b, err := json.Marshal(n)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(b) != "1516239022" {
+		t.Errorf("expected 1516239022, got %s", b)
+	}
+
+	var decoded NumericDate
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.Time.Equal(n.Time) {
+		t.Errorf("expected %v, got %v", n.Time, decoded.Time)
+	}
+}
+
+func TestValidateStandardClaims(t *testing.T) {
+	now := time.Unix(1000000, 0)
+
+	tests := []struct {
+		name             string
+		claims           RegisteredClaims
+		expectedAudience string
+		expectedIssuer   string
+		leeway           time.Duration
+		wantErr          bool
+	}{
+		{
+			name:   "valid claims",
+			claims: RegisteredClaims{Issuer: "https://issuer.example", Audience: Audience{"verifier"}},
+			expectedAudience: "verifier", expectedIssuer: "https://issuer.example",
+		},
+		{
+			name:           "wrong issuer",
+			claims:         RegisteredClaims{Issuer: "https://other.example"},
+			expectedIssuer: "https://issuer.example",
+			wantErr:        true,
+		},
+		{
+			name:             "missing expected audience",
+			claims:           RegisteredClaims{Audience: Audience{"someone-else"}},
+			expectedAudience: "verifier",
+			wantErr:          true,
+		},
+		{
+			name:    "expired",
+			claims:  RegisteredClaims{ExpirationTime: &NumericDate{now.Add(-time.Hour)}},
+			wantErr: true,
+		},
+		{
+			name:   "expired but within leeway",
+			claims: RegisteredClaims{ExpirationTime: &NumericDate{now.Add(-time.Second)}},
+			leeway: time.Minute,
+		},
+		{
+			name:    "not yet valid",
+			claims:  RegisteredClaims{NotBefore: &NumericDate{now.Add(time.Hour)}},
+			wantErr: true,
+		},
+		{
+			name:   "not yet valid but within leeway",
+			claims: RegisteredClaims{NotBefore: &NumericDate{now.Add(time.Second)}},
+			leeway: time.Minute,
+		},
+		{
+			name:    "issued in the future",
+			claims:  RegisteredClaims{IssuedAt: &NumericDate{now.Add(time.Hour)}},
+			wantErr: true,
+		},
+		{
+			name:   "issued in the future but within leeway",
+			claims: RegisteredClaims{IssuedAt: &NumericDate{now.Add(time.Second)}},
+			leeway: time.Minute,
+		},
+	}
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := NewIssuer(&EdDSASigner{PrivateKey: priv})
+			b, err := json.Marshal(tt.claims)
+			if err != nil {
+				t.Fatalf("failed to marshal claims: %v", err)
+			}
+			var m map[string]any
+			if err := json.Unmarshal(b, &m); err != nil {
+				t.Fatalf("failed to unmarshal claims: %v", err)
+			}
+			for k, v := range m {
+				issuer.AddAlwaysVisible(k, v)
+			}
+
+			token, _, err := issuer.Sign()
+			if err != nil {
+				t.Fatalf("failed to sign: %v", err)
+			}
+			sdJwt, err := New(token)
+			if err != nil {
+				t.Fatalf("failed to parse issued token: %v", err)
+			}
+
+			err = sdJwt.ValidateStandardClaims(now, tt.expectedAudience, tt.expectedIssuer, tt.leeway)
+			if tt.wantErr && err == nil {
+				t.Errorf("expected an error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeClaims(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	token, _, err := NewIssuer(&EdDSASigner{PrivateKey: priv}).
+		AddAlwaysVisible("iss", "https://issuer.example").
+		AddSelectivelyDisclosable("sub", "user-1").
+		Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	var claims RegisteredClaims
+	if err := sdJwt.DecodeClaims(&claims); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Issuer != "https://issuer.example" {
+		t.Errorf("expected issuer to be decoded, got %q", claims.Issuer)
+	}
+	if claims.Subject != "user-1" {
+		t.Errorf("expected disclosed sub to be decoded, got %q", claims.Subject)
+	}
+}
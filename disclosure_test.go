@@ -0,0 +1,76 @@
+package go_sd_jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+)
+
+func mustDisclosure(t *testing.T, raw string) *Disclosure {
+	t.Helper()
+
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(raw))
+	d, err := newDisclosure([]byte(encoded))
+	if err != nil {
+		t.Fatalf("failed to parse disclosure %q: %v", raw, err)
+	}
+	return d
+}
+
+// TestNewDisclosureCommaInNestedValue exercises the exact case the
+// strings.Split-based parser used to corrupt: a claim value containing a
+// comma inside a nested JSON object.
+func TestNewDisclosureCommaInNestedValue(t *testing.T) {
+	d := mustDisclosure(t, `["2GLC42sKQveCfGfryNRN9w","address",{"street_address":"1, Main St","locality":"Anytown"}]`)
+
+	if d.Salt() != "2GLC42sKQveCfGfryNRN9w" {
+		t.Errorf("unexpected salt: %s", d.Salt())
+	}
+	if d.ClaimName() == nil || *d.ClaimName() != "address" {
+		t.Fatalf("unexpected claim name: %v", d.ClaimName())
+	}
+
+	var value map[string]any
+	if err := json.Unmarshal(d.ClaimValueJSON(), &value); err != nil {
+		t.Fatalf("ClaimValueJSON did not round-trip as JSON: %v", err)
+	}
+	if value["street_address"] != "1, Main St" {
+		t.Errorf("unexpected street_address: %v", value["street_address"])
+	}
+	if value["locality"] != "Anytown" {
+		t.Errorf("unexpected locality: %v", value["locality"])
+	}
+}
+
+// TestNewDisclosureCommaInArrayValue covers the array-element disclosure
+// form (two parts, no claim name) with a comma inside a nested array value.
+func TestNewDisclosureCommaInArrayValue(t *testing.T) {
+	d := mustDisclosure(t, `["eluV5Og3gSNII8EYnsxA_A",["Bob, Jr.","Bobby"]]`)
+
+	if d.ClaimName() != nil {
+		t.Fatalf("expected no claim name for an array element disclosure, got %v", d.ClaimName())
+	}
+
+	var value []string
+	if err := json.Unmarshal(d.ClaimValueJSON(), &value); err != nil {
+		t.Fatalf("ClaimValueJSON did not round-trip as JSON: %v", err)
+	}
+	if len(value) != 2 || value[0] != "Bob, Jr." || value[1] != "Bobby" {
+		t.Errorf("unexpected claim value: %v", value)
+	}
+}
+
+// TestNewDisclosureClaimValueBackwardCompat checks that ClaimValue retains
+// its pre-existing string semantics: unquoted for JSON strings, raw JSON
+// text for anything else.
+func TestNewDisclosureClaimValueBackwardCompat(t *testing.T) {
+	strDisclosure := mustDisclosure(t, `["salt","given_name","Alice"]`)
+	if strDisclosure.ClaimValue() != "Alice" {
+		t.Errorf("expected unquoted string value, got %q", strDisclosure.ClaimValue())
+	}
+
+	objDisclosure := mustDisclosure(t, `["salt","address",{"region":"Anystate"}]`)
+	if objDisclosure.ClaimValue() != `{"region":"Anystate"}` {
+		t.Errorf("expected raw JSON text for object value, got %q", objDisclosure.ClaimValue())
+	}
+}
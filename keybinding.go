@@ -0,0 +1,176 @@
+package go_sd_jwt
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// kbJwtFreshnessWindow bounds how far a Key Binding JWT's "iat" may drift
+// from the verifier's clock, in either direction, before it is rejected.
+const kbJwtFreshnessWindow = 5 * time.Minute
+
+// KeyBindingJwt represents the holder-signed JWT that binds a presentation of
+// an SD-JWT to a particular verifier interaction (audience and nonce).
+type KeyBindingJwt struct {
+	token        string
+	head         map[string]any
+	body         map[string]any
+	signature    string
+	signingInput string
+}
+
+// Head returns the Key Binding JWT's header.
+func (k *KeyBindingJwt) Head() map[string]any {
+	return k.head
+}
+
+// Body returns the Key Binding JWT's claims.
+func (k *KeyBindingJwt) Body() map[string]any {
+	return k.body
+}
+
+// Token returns the Key Binding JWT as it was received.
+func (k *KeyBindingJwt) Token() string {
+	return k.token
+}
+
+func parseKeyBindingJwt(token string) (*KeyBindingJwt, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("key binding JWT is not a valid JWT")
+	}
+
+	hb, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	var head map[string]any
+	if err := json.Unmarshal(hb, &head); err != nil {
+		return nil, err
+	}
+
+	bb, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	var body map[string]any
+	if err := json.Unmarshal(bb, &body); err != nil {
+		return nil, err
+	}
+
+	return &KeyBindingJwt{
+		token:        token,
+		head:         head,
+		body:         body,
+		signature:    parts[2],
+		signingInput: parts[0] + "." + parts[1],
+	}, nil
+}
+
+// verify checks the Key Binding JWT's signature against the holder's public key.
+func (k *KeyBindingJwt) verify(holderKey crypto.PublicKey) error {
+	alg, _ := k.head["alg"].(string)
+	if alg == "" {
+		return errors.New("key binding JWT has no alg")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(k.signature)
+	if err != nil {
+		return err
+	}
+
+	return verifySignature(alg, holderKey, []byte(k.signingInput), sig)
+}
+
+// VerifyKeyBinding checks that the SD-JWT's presented Key Binding JWT is
+// valid: its signature matches the holder key bound to this credential via
+// the "cnf.jwk" confirmation claim, its "aud" and "nonce" match what the
+// verifier expects, its "iat" is fresh relative to now, its "typ" is
+// "kb+jwt", and its "sd_hash" matches the disclosures actually presented.
+func (s *SdJwt) VerifyKeyBinding(audience string, nonce string, now time.Time) error {
+	kb := s.KeyBindingJwt()
+	if kb == nil {
+		return errors.New("SD-JWT does not contain a key binding JWT")
+	}
+
+	if typ, _ := kb.head["typ"].(string); typ != "kb+jwt" {
+		return errors.New("key binding JWT has unexpected typ")
+	}
+
+	holderKey, err := s.holderPublicKey()
+	if err != nil {
+		return err
+	}
+
+	if err := kb.verify(holderKey); err != nil {
+		return err
+	}
+
+	if kbAud, _ := kb.body["aud"].(string); kbAud != audience {
+		return errors.New("key binding JWT aud does not match expected audience")
+	}
+
+	if kbNonce, _ := kb.body["nonce"].(string); kbNonce != nonce {
+		return errors.New("key binding JWT nonce does not match expected nonce")
+	}
+
+	iat, ok := kb.body["iat"].(float64)
+	if !ok {
+		return errors.New("key binding JWT is missing iat")
+	}
+	issuedAt := time.Unix(int64(iat), 0)
+	if issuedAt.Before(now.Add(-kbJwtFreshnessWindow)) || issuedAt.After(now.Add(kbJwtFreshnessWindow)) {
+		return errors.New("key binding JWT iat is not fresh")
+	}
+
+	expectedHash := s.sdHash()
+	if actualHash, _ := kb.body["sd_hash"].(string); actualHash != expectedHash {
+		return errors.New("key binding JWT sd_hash does not match the presented disclosures")
+	}
+
+	return nil
+}
+
+// holderPublicKey extracts and decodes the holder's public key from this
+// SD-JWT's "cnf.jwk" confirmation claim.
+func (s *SdJwt) holderPublicKey() (crypto.PublicKey, error) {
+	cnf, ok := s.body["cnf"].(map[string]any)
+	if !ok {
+		return nil, errors.New("SD-JWT body has no cnf claim")
+	}
+	jwkClaim, ok := cnf["jwk"].(map[string]any)
+	if !ok {
+		return nil, errors.New("cnf claim has no jwk")
+	}
+
+	b, err := json.Marshal(jwkClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwk JWK
+	if err := json.Unmarshal(b, &jwk); err != nil {
+		return nil, err
+	}
+
+	return jwk.PublicKey()
+}
+
+// sdHash recomputes the base64url sha-256 digest over the
+// "<issuer-jwt>~<disclosure1>~...~" string that a Key Binding JWT's
+// "sd_hash" claim must match.
+func (s *SdJwt) sdHash() string {
+	presented := s.Token()
+	for _, d := range s.disclosures {
+		presented += "~" + d.EncodedValue()
+	}
+	presented += "~"
+
+	h := sha256.Sum256([]byte(presented))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
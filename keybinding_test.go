@@ -0,0 +1,126 @@
+package go_sd_jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// issueWithHolderBinding builds an SD-JWT that confirms the given holder
+// public key via "cnf.jwk", signed by an independent issuer key.
+func issueWithHolderBinding(t *testing.T, holderPub ed25519.PublicKey) string {
+	t.Helper()
+
+	_, issuerPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate issuer key: %v", err)
+	}
+
+	cnf := map[string]any{
+		"jwk": map[string]any{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"x":   base64.RawURLEncoding.EncodeToString(holderPub),
+		},
+	}
+
+	token, _, err := NewIssuer(&EdDSASigner{PrivateKey: issuerPriv}).
+		AddAlwaysVisible("iss", "https://example.com").
+		AddAlwaysVisible("cnf", cnf).
+		AddSelectivelyDisclosable("given_name", "Alice").
+		Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	return token
+}
+
+func TestVerifyKeyBinding(t *testing.T) {
+	holderPub, holderPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate holder key: %v", err)
+	}
+	_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate other key: %v", err)
+	}
+
+	issuedToken := issueWithHolderBinding(t, holderPub)
+
+	present := func(t *testing.T, signer Signer, audience, nonce string) *SdJwt {
+		t.Helper()
+		issued, err := New(issuedToken)
+		if err != nil {
+			t.Fatalf("failed to parse issued token: %v", err)
+		}
+		presentation, err := issued.Present(
+			func(d Disclosure, path []string) bool { return true },
+			&KeyBindingRequest{Audience: audience, Nonce: nonce, Signer: signer},
+		)
+		if err != nil {
+			t.Fatalf("failed to present: %v", err)
+		}
+		presented, err := New(presentation)
+		if err != nil {
+			t.Fatalf("failed to parse presentation: %v", err)
+		}
+		return presented
+	}
+
+	t.Run("valid key binding passes", func(t *testing.T) {
+		presented := present(t, &EdDSASigner{PrivateKey: holderPriv}, "verifier", "nonce-1")
+		if err := presented.VerifyKeyBinding("verifier", "nonce-1", time.Now()); err != nil {
+			t.Errorf("expected valid key binding to verify, got: %v", err)
+		}
+	})
+
+	t.Run("wrong audience fails", func(t *testing.T) {
+		presented := present(t, &EdDSASigner{PrivateKey: holderPriv}, "verifier", "nonce-1")
+		if err := presented.VerifyKeyBinding("someone-else", "nonce-1", time.Now()); err == nil {
+			t.Errorf("expected mismatched audience to fail")
+		}
+	})
+
+	t.Run("stale iat fails", func(t *testing.T) {
+		presented := present(t, &EdDSASigner{PrivateKey: holderPriv}, "verifier", "nonce-1")
+		future := time.Now().Add(kbJwtFreshnessWindow * 3)
+		if err := presented.VerifyKeyBinding("verifier", "nonce-1", future); err == nil {
+			t.Errorf("expected stale iat to fail")
+		}
+	})
+
+	t.Run("tampered sd_hash fails", func(t *testing.T) {
+		presented := present(t, &EdDSASigner{PrivateKey: holderPriv}, "verifier", "nonce-1")
+		presented.kbJwt.body["sd_hash"] = "tampered-hash"
+		if err := presented.VerifyKeyBinding("verifier", "nonce-1", time.Now()); err == nil {
+			t.Errorf("expected tampered sd_hash to fail")
+		}
+	})
+
+	t.Run("mismatched holder key fails", func(t *testing.T) {
+		presented := present(t, &EdDSASigner{PrivateKey: otherPriv}, "verifier", "nonce-1")
+		if err := presented.VerifyKeyBinding("verifier", "nonce-1", time.Now()); err == nil {
+			t.Errorf("expected key binding JWT signed by a non-bound key to fail")
+		}
+	})
+
+	t.Run("no key binding JWT fails", func(t *testing.T) {
+		issued, err := New(issuedToken)
+		if err != nil {
+			t.Fatalf("failed to parse issued token: %v", err)
+		}
+		presentation, err := issued.Present(func(d Disclosure, path []string) bool { return true }, nil)
+		if err != nil {
+			t.Fatalf("failed to present: %v", err)
+		}
+		presented, err := New(presentation)
+		if err != nil {
+			t.Fatalf("failed to parse presentation: %v", err)
+		}
+		if err := presented.VerifyKeyBinding("verifier", "nonce-1", time.Now()); err == nil {
+			t.Errorf("expected missing key binding JWT to fail")
+		}
+	})
+}
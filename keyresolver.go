@@ -0,0 +1,309 @@
+package go_sd_jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyResolver locates the public key that should be used to verify an SD-JWT,
+// based on the "kid" and "alg" values found in its header. Implementations
+// range from a single fixed key to a remote, cached JWKS endpoint.
+type KeyResolver interface {
+	ResolveKey(kid string, alg string) (crypto.PublicKey, error)
+}
+
+// StaticKeyResolver always resolves to the same public key, regardless of kid
+// or alg. Useful when the caller already knows which key signed the SD-JWT.
+type StaticKeyResolver struct {
+	Key crypto.PublicKey
+}
+
+// ResolveKey returns the static key.
+func (r *StaticKeyResolver) ResolveKey(_ string, _ string) (crypto.PublicKey, error) {
+	if r.Key == nil {
+		return nil, errors.New("no key configured on StaticKeyResolver")
+	}
+	return r.Key, nil
+}
+
+// JWK is a minimal JSON Web Key, covering the RSA, EC and OKP (Ed25519) key
+// types needed to verify RS256/PS256, ES256/ES384 and EdDSA respectively.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+}
+
+// PublicKey decodes the JWK into the concrete crypto.PublicKey it represents.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		eInt := 0
+		for _, b := range e {
+			eInt = eInt<<8 + int(b)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: eInt}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, errors.New("unsupported EC curve: " + k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, errors.New("unsupported OKP curve: " + k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, errors.New("unsupported JWK key type: " + k.Kty)
+	}
+}
+
+// JWKSet is a static set of JWKs, resolved by matching "kid" (and falling
+// back to "alg" when there is a single unambiguous candidate).
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ResolveKey finds the JWK matching kid/alg in the set and returns its public key.
+func (s *JWKSet) ResolveKey(kid string, alg string) (crypto.PublicKey, error) {
+	jwk, err := s.find(kid, alg)
+	if err != nil {
+		return nil, err
+	}
+	return jwk.PublicKey()
+}
+
+func (s *JWKSet) find(kid string, alg string) (*JWK, error) {
+	if kid != "" {
+		for i := range s.Keys {
+			if s.Keys[i].Kid == kid {
+				return &s.Keys[i], nil
+			}
+		}
+		return nil, errors.New("no JWK found for kid: " + kid)
+	}
+
+	var candidates []*JWK
+	for i := range s.Keys {
+		if alg == "" || s.Keys[i].Alg == "" || s.Keys[i].Alg == alg {
+			candidates = append(candidates, &s.Keys[i])
+		}
+	}
+	if len(candidates) != 1 {
+		return nil, errors.New("cannot disambiguate JWK without a kid")
+	}
+	return candidates[0], nil
+}
+
+// refreshAheadWindow is how far before its cache entry expires a
+// RemoteKeyResolver starts a background refresh, so that in steady state a
+// Verify call only ever pays for a synchronous fetch on the very first
+// lookup (or after an unreachable JWKS endpoint lets the cache lapse).
+const refreshAheadWindow = 1 * time.Minute
+
+// RemoteKeyResolver resolves keys from a remote JWKS endpoint, caching the
+// fetched set in memory. A lookup that finds its kid in an unexpired cache
+// entry never blocks on a network fetch: as the entry nears expiry, a
+// refresh is kicked off in the background (deduplicated, so concurrent
+// callers never trigger more than one fetch at a time) while callers keep
+// using the still-valid cached set. A lookup against a cold or expired
+// cache, or for a kid the cached set does not contain, waits for a fetch,
+// which concurrent callers share.
+type RemoteKeyResolver struct {
+	URL        string
+	HTTPClient *http.Client
+
+	mu     sync.RWMutex
+	cached *JWKSet
+	expiry time.Time
+
+	refreshing sync.Mutex
+}
+
+// NewRemoteKeyResolver creates a RemoteKeyResolver for the given JWKS URL.
+func NewRemoteKeyResolver(url string) *RemoteKeyResolver {
+	return &RemoteKeyResolver{URL: url, HTTPClient: http.DefaultClient}
+}
+
+// ResolveKey returns the public key for kid/alg, fetching the JWKS if the
+// cache is cold or expired or is missing the requested kid, and refreshing
+// it in the background as it nears expiry.
+func (r *RemoteKeyResolver) ResolveKey(kid string, alg string) (crypto.PublicKey, error) {
+	if jwk, ok := r.lookupCached(kid, alg); ok {
+		return jwk.PublicKey()
+	}
+
+	set, err := r.refreshNow()
+	if err != nil {
+		return nil, err
+	}
+
+	jwk, err := set.find(kid, alg)
+	if err != nil {
+		return nil, err
+	}
+	return jwk.PublicKey()
+}
+
+// lookupCached returns the cached JWK for kid/alg, if any, without ever
+// blocking on a network fetch.
+func (r *RemoteKeyResolver) lookupCached(kid string, alg string) (*JWK, bool) {
+	r.mu.RLock()
+	cached, expiry := r.cached, r.expiry
+	r.mu.RUnlock()
+
+	if cached == nil {
+		return nil, false
+	}
+
+	if time.Until(expiry) < refreshAheadWindow {
+		r.refreshInBackground()
+	}
+	if time.Now().After(expiry) {
+		return nil, false
+	}
+
+	jwk, err := cached.find(kid, alg)
+	if err != nil {
+		// The cached set may simply be stale (a rotated kid); refresh in the
+		// background and let this caller fall through to a synchronous fetch.
+		r.refreshInBackground()
+		return nil, false
+	}
+	return jwk, true
+}
+
+// refreshInBackground starts a refresh without blocking the caller. At most
+// one refresh runs at a time; if one is already in flight this is a no-op.
+func (r *RemoteKeyResolver) refreshInBackground() {
+	if !r.refreshing.TryLock() {
+		return
+	}
+	go func() {
+		defer r.refreshing.Unlock()
+		_, _ = r.doRefresh()
+	}()
+}
+
+// refreshNow returns a current JWKS, blocking the caller. Concurrent callers
+// serialize behind refreshing, so a cold cache only ever triggers a single
+// network round trip: if the cache holds an unexpired entry once the lock is
+// held (another caller or a background refresh just fetched it), that entry
+// is returned instead of fetching again.
+func (r *RemoteKeyResolver) refreshNow() (*JWKSet, error) {
+	r.refreshing.Lock()
+	defer r.refreshing.Unlock()
+
+	r.mu.RLock()
+	cached, expiry := r.cached, r.expiry
+	r.mu.RUnlock()
+	if cached != nil && time.Now().Before(expiry) {
+		return cached, nil
+	}
+	return r.doRefresh()
+}
+
+// doRefresh performs the actual fetch and stores the result in the cache,
+// assuming refreshing is already held by the caller.
+func (r *RemoteKeyResolver) doRefresh() (*JWKSet, error) {
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unexpected status fetching JWKS: " + resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var set JWKSet
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cached = &set
+	r.expiry = time.Now().Add(cacheTTL(resp.Header.Get("Cache-Control")))
+	r.mu.Unlock()
+
+	return &set, nil
+}
+
+// cacheTTL parses a Cache-Control header and returns how long the response
+// may be reused. The first max-age, no-cache or no-store directive decides:
+// max-age gives its value in seconds, no-cache and no-store give zero. With
+// none of these, or a max-age that is not a non-negative integer, it returns
+// five minutes.
+func cacheTTL(cacheControl string) time.Duration {
+	const defaultTTL = 5 * time.Minute
+
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if strings.HasPrefix(directive, "max-age=") {
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || seconds < 0 {
+				return defaultTTL
+			}
+			return time.Duration(seconds) * time.Second
+		}
+		if directive == "no-cache" || directive == "no-store" {
+			return 0
+		}
+	}
+
+	return defaultTTL
+}
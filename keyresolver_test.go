@@ -0,0 +1,100 @@
+package go_sd_jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestRemoteKeyResolverCachesWarmReads checks that once the JWKS has been
+// fetched, concurrent ResolveKey calls are served from cache without
+// triggering further network requests.
+func TestRemoteKeyResolverCachesWarmReads(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=120")
+		_ = json.NewEncoder(w).Encode(JWKSet{Keys: []JWK{{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+			Kid: "kid-1",
+		}}})
+	}))
+	defer server.Close()
+
+	resolver := NewRemoteKeyResolver(server.URL)
+
+	if _, err := resolver.ResolveKey("kid-1", "EdDSA"); err != nil {
+		t.Fatalf("unexpected error on cold lookup: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected exactly 1 request after cold lookup, got %d", got)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := resolver.ResolveKey("kid-1", "EdDSA"); err != nil {
+				t.Errorf("unexpected error on warm lookup: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected warm reads to be served from cache, got %d requests", got)
+	}
+}
+
+// TestRemoteKeyResolverUnknownKidFetchesOnce checks that concurrent lookups
+// against a cold cache, where no kid is known yet, share a single fetch
+// rather than making one fetch per caller.
+func TestRemoteKeyResolverUnknownKidFetchesOnce(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=120")
+		_ = json.NewEncoder(w).Encode(JWKSet{Keys: []JWK{{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+			Kid: "kid-1",
+		}}})
+	}))
+	defer server.Close()
+
+	resolver := NewRemoteKeyResolver(server.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = resolver.ResolveKey("kid-1", "EdDSA")
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("expected concurrent cold lookups to share a single fetch, got %d requests", got)
+	}
+}
@@ -0,0 +1,135 @@
+package go_sd_jwt
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// KeyBindingRequest carries what a holder needs to produce a fresh Key
+// Binding JWT when presenting an SD-JWT to a particular verifier.
+type KeyBindingRequest struct {
+	Audience string
+	Nonce    string
+	Signer   Signer
+}
+
+// Present builds a new compact SD-JWT presentation containing only the
+// disclosures selector accepts, optionally followed by a freshly signed
+// Key Binding JWT.
+//
+// selector is called once per disclosure held by this SD-JWT with the
+// JSON-pointer-like path the disclosure would occupy once reconstructed into
+// the body, e.g. []string{"address", "region"} or []string{"nicknames", "[1]"}
+// for an array element. Returning true discloses it.
+//
+// If kb is non-nil, a Key Binding JWT is signed with kb.Signer over the
+// resulting presentation and appended, per the SD-JWT specification's
+// "<issuer-jwt>~<disclosure1>~...~<disclosureN>~<kb-jwt>" format.
+func (s *SdJwt) Present(selector func(d Disclosure, path []string) bool, kb *KeyBindingRequest) (string, error) {
+	digestToPath := s.disclosurePaths()
+
+	var kept []Disclosure
+	for _, d := range s.disclosures {
+		path := digestToPath[d.EncodedValue()]
+		if selector(d, path) {
+			kept = append(kept, d)
+		}
+	}
+
+	presentation := s.Token() + "~"
+	for _, d := range kept {
+		presentation += d.EncodedValue() + "~"
+	}
+
+	if kb == nil {
+		return presentation, nil
+	}
+	if kb.Signer == nil {
+		return "", errors.New("key binding request requires a Signer")
+	}
+
+	sdHashBytes := sha256.Sum256([]byte(presentation))
+	head := map[string]any{"alg": kb.Signer.Algorithm(), "typ": "kb+jwt"}
+	body := map[string]any{
+		"aud":     kb.Audience,
+		"nonce":   kb.Nonce,
+		"iat":     time.Now().Unix(),
+		"sd_hash": base64.RawURLEncoding.EncodeToString(sdHashBytes[:]),
+	}
+
+	headBytes, err := json.Marshal(head)
+	if err != nil {
+		return "", err
+	}
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headBytes) + "." + base64.RawURLEncoding.EncodeToString(bodyBytes)
+	sig, err := kb.Signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return presentation + signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// disclosurePaths maps each of this SD-JWT's disclosures, keyed by its
+// encoded value, to the path it occupies in the body once reconstructed.
+func (s *SdJwt) disclosurePaths() map[string][]string {
+	byDigest := map[string]Disclosure{}
+	for _, d := range s.disclosures {
+		byDigest[digestOf(d.EncodedValue())] = d
+	}
+
+	paths := map[string][]string{}
+	walkForPaths(s.body, nil, byDigest, paths)
+	return paths
+}
+
+func walkForPaths(node any, path []string, byDigest map[string]Disclosure, paths map[string][]string) {
+	switch v := node.(type) {
+	case map[string]any:
+		if sd, ok := v["_sd"].([]any); ok {
+			for _, digest := range sd {
+				digestStr, ok := digest.(string)
+				if !ok {
+					continue
+				}
+				if d, found := byDigest[digestStr]; found && d.ClaimName() != nil {
+					paths[d.EncodedValue()] = appendPath(path, *d.ClaimName())
+				}
+			}
+		}
+		for k, v2 := range v {
+			if k == "_sd" || k == "_sd_alg" {
+				continue
+			}
+			walkForPaths(v2, appendPath(path, k), byDigest, paths)
+		}
+	case []any:
+		for i, elem := range v {
+			if m, ok := elem.(map[string]any); ok {
+				if digestStr, ok := m["..."].(string); ok {
+					if d, found := byDigest[digestStr]; found {
+						paths[d.EncodedValue()] = appendPath(path, fmt.Sprintf("[%d]", i))
+						continue
+					}
+				}
+			}
+			walkForPaths(elem, appendPath(path, fmt.Sprintf("[%d]", i)), byDigest, paths)
+		}
+	}
+}
+
+func appendPath(path []string, next string) []string {
+	out := make([]string, len(path)+1)
+	copy(out, path)
+	out[len(path)] = next
+	return out
+}
@@ -0,0 +1,170 @@
+package go_sd_jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+// buildNestedPresentationToken issues an SD-JWT with a selectively
+// disclosable top-level claim, a selectively disclosable nested claim under
+// "address", and a selectively disclosable array element under "nicknames",
+// mirroring the address.region example from the SD-JWT specification.
+func buildNestedPresentationToken(t *testing.T) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	region := mustDisclosure(t, `["salt-region","region","Anystate"]`)
+	nickname := mustDisclosure(t, `["salt-nick","Bobby"]`)
+
+	issuer := NewIssuer(&EdDSASigner{PrivateKey: priv}).
+		AddAlwaysVisible("iss", "https://example.com").
+		AddSelectivelyDisclosable("given_name", "Alice")
+	issuer.disclosures = append(issuer.disclosures, *region, *nickname)
+	issuer.alwaysVisible["address"] = map[string]any{
+		"_sd":     []any{digestOf(region.EncodedValue())},
+		"country": "US",
+	}
+	issuer.alwaysVisible["nicknames"] = []any{
+		arrayDisclosure{Digest: stringPtr(digestOf(nickname.EncodedValue()))},
+	}
+
+	token, _, err := issuer.Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	return token, priv
+}
+
+func stringPtr(s string) *string { return &s }
+
+func TestDisclosurePaths(t *testing.T) {
+	token, _ := buildNestedPresentationToken(t)
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	paths := sdJwt.disclosurePaths()
+
+	var gotGivenName, gotRegion, gotNickname bool
+	for _, d := range sdJwt.disclosures {
+		path := paths[d.EncodedValue()]
+		switch {
+		case d.ClaimName() != nil && *d.ClaimName() == "given_name":
+			gotGivenName = len(path) == 1 && path[0] == "given_name"
+		case d.ClaimName() != nil && *d.ClaimName() == "region":
+			gotRegion = len(path) == 2 && path[0] == "address" && path[1] == "region"
+		case d.ClaimName() == nil:
+			gotNickname = len(path) == 2 && path[0] == "nicknames" && path[1] == "[0]"
+		}
+	}
+
+	if !gotGivenName {
+		t.Errorf("expected given_name to resolve to path [given_name], got %v", paths)
+	}
+	if !gotRegion {
+		t.Errorf("expected region to resolve to path [address region], got %v", paths)
+	}
+	if !gotNickname {
+		t.Errorf("expected array element to resolve to path [nicknames [0]], got %v", paths)
+	}
+}
+
+func TestPresentSelectsByPath(t *testing.T) {
+	token, _ := buildNestedPresentationToken(t)
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	presentation, err := sdJwt.Present(func(d Disclosure, path []string) bool {
+		return len(path) == 2 && path[0] == "address" && path[1] == "region"
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to present: %v", err)
+	}
+
+	presented, err := New(presentation)
+	if err != nil {
+		t.Fatalf("failed to parse presentation: %v", err)
+	}
+
+	if len(presented.disclosures) != 1 {
+		t.Fatalf("expected exactly 1 disclosure to be kept, got %d", len(presented.disclosures))
+	}
+	if presented.disclosures[0].ClaimName() == nil || *presented.disclosures[0].ClaimName() != "region" {
+		t.Errorf("expected the kept disclosure to be region, got %+v", presented.disclosures[0])
+	}
+
+	if !strings.HasSuffix(presentation, "~") {
+		t.Errorf("expected presentation with no key binding JWT to end with a trailing ~")
+	}
+}
+
+func TestPresentWithKeyBindingAppendsKbJwt(t *testing.T) {
+	token, _ := buildNestedPresentationToken(t)
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	_, holderPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate holder key: %v", err)
+	}
+
+	presentation, err := sdJwt.Present(
+		func(d Disclosure, path []string) bool { return true },
+		&KeyBindingRequest{Audience: "verifier", Nonce: "nonce-1", Signer: &EdDSASigner{PrivateKey: holderPriv}},
+	)
+	if err != nil {
+		t.Fatalf("failed to present: %v", err)
+	}
+
+	presented, err := New(presentation)
+	if err != nil {
+		t.Fatalf("failed to parse presentation: %v", err)
+	}
+
+	kb := presented.KeyBindingJwt()
+	if kb == nil {
+		t.Fatal("expected a key binding JWT to be attached")
+	}
+	if kb.Body()["aud"] != "verifier" {
+		t.Errorf("expected kb-jwt aud to be verifier, got %v", kb.Body()["aud"])
+	}
+	if kb.Body()["nonce"] != "nonce-1" {
+		t.Errorf("expected kb-jwt nonce to be nonce-1, got %v", kb.Body()["nonce"])
+	}
+}
+
+func TestPresentNoDisclosuresSelected(t *testing.T) {
+	token, _ := buildNestedPresentationToken(t)
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	presentation, err := sdJwt.Present(func(d Disclosure, path []string) bool { return false }, nil)
+	if err != nil {
+		t.Fatalf("failed to present: %v", err)
+	}
+
+	presented, err := New(presentation)
+	if err != nil {
+		t.Fatalf("failed to parse presentation with no disclosures: %v", err)
+	}
+	if len(presented.disclosures) != 0 {
+		t.Errorf("expected 0 disclosures, got %d", len(presented.disclosures))
+	}
+}
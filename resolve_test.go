@@ -0,0 +1,74 @@
+package go_sd_jwt
+
+import (
+	"testing"
+)
+
+// TestResolveSDClaimsNestedAndArrays exercises the single-pass resolver
+// directly against a hand-built body covering a top-level _sd claim, a
+// nested _sd claim, an array element disclosure, and a decoy digest that
+// has no matching disclosure.
+func TestResolveSDClaimsNestedAndArrays(t *testing.T) {
+	givenName := mustDisclosure(t, `["salt1","given_name","Alice"]`)
+	region := mustDisclosure(t, `["salt2","region","Anystate"]`)
+	nickname := mustDisclosure(t, `["salt3","Bobby"]`)
+
+	decoyDigest := digestOf("not-a-real-disclosure")
+
+	body := map[string]any{
+		"_sd_alg": "sha-256",
+		"_sd":     []any{digestOf(givenName.EncodedValue()), decoyDigest},
+		"address": map[string]any{
+			"_sd":     []any{digestOf(region.EncodedValue())},
+			"country": "US",
+		},
+		"nicknames": []any{
+			map[string]any{"...": digestOf(nickname.EncodedValue())},
+			"Al",
+		},
+	}
+
+	byDigest := map[string]*Disclosure{
+		digestOf(givenName.EncodedValue()): givenName,
+		digestOf(region.EncodedValue()):    region,
+		digestOf(nickname.EncodedValue()):  nickname,
+	}
+	used := map[string]bool{}
+	dst := map[string]any{}
+
+	if err := resolveSDClaims(body, dst, byDigest, used); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dst["given_name"] != "Alice" {
+		t.Errorf("expected top-level given_name to be disclosed, got %v", dst["given_name"])
+	}
+
+	addr, ok := dst["address"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected address to be a map, got %T", dst["address"])
+	}
+	if addr["region"] != "Anystate" {
+		t.Errorf("expected nested region to be disclosed, got %v", addr["region"])
+	}
+	if _, ok := addr["_sd"]; ok {
+		t.Errorf("expected nested _sd to be stripped from the disclosed claims")
+	}
+
+	nicknames, ok := dst["nicknames"].([]any)
+	if !ok || len(nicknames) != 2 {
+		t.Fatalf("expected 2 resolved nicknames, got %v", dst["nicknames"])
+	}
+	if nicknames[0] != "Bobby" {
+		t.Errorf("expected array element disclosure to resolve to Bobby, got %v", nicknames[0])
+	}
+	if nicknames[1] != "Al" {
+		t.Errorf("expected plaintext array element to be left untouched, got %v", nicknames[1])
+	}
+
+	for digest := range byDigest {
+		if !used[digest] {
+			t.Errorf("digest %s was never marked used", digest)
+		}
+	}
+}
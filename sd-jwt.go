@@ -8,29 +8,29 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
-	"hash"
-	"reflect"
 	"strings"
 )
 
 // SdJwt this object represents a valid SD-JWT. Created using the New function which performs the required validation.
 // Helper methods are provided for retrieving the contents
 type SdJwt struct {
-	token       string
-	head        map[string]any
-	body        map[string]any
-	signature   string
-	disclosures []Disclosure
+	token        string
+	head         map[string]any
+	body         map[string]any
+	signature    string
+	signingInput string
+	disclosures  []Disclosure
+	kbJwt        *KeyBindingJwt
 }
 
 // Disclosure this object represents a single disclosure in a SD-JWT.
 // Helper methods are provided for retrieving the contents
 type Disclosure struct {
-	salt         string
-	claimName    *string
-	claimValue   string
-	rawValue     string
-	encodedValue string
+	salt           string
+	claimName      *string
+	claimValueJSON json.RawMessage
+	rawValue       string
+	encodedValue   string
 }
 
 type jwsSdJwt struct {
@@ -86,6 +86,7 @@ func validateJws(token jwsSdJwt) (*SdJwt, error) {
 	sdJwt.head = head
 
 	sdJwt.signature = *token.Signature
+	sdJwt.signingInput = *token.Protected + "." + *token.Payload
 
 	disclosures, err := validateDisclosures(token.Disclosures)
 	if err != nil {
@@ -94,6 +95,14 @@ func validateJws(token jwsSdJwt) (*SdJwt, error) {
 
 	sdJwt.disclosures = disclosures
 
+	if token.KbJwt != nil && *token.KbJwt != "" {
+		kbJwt, err := parseKeyBindingJwt(*token.KbJwt)
+		if err != nil {
+			return nil, err
+		}
+		sdJwt.kbJwt = kbJwt
+	}
+
 	b, err = base64.RawURLEncoding.DecodeString(*token.Payload)
 	if err != nil {
 		return nil, err
@@ -125,7 +134,19 @@ func validateJwt(token string) (*SdJwt, error) {
 
 	sdJwt.token = sections[0]
 
-	tokenSections := strings.Split(token, ".")
+	// A trailing KB-JWT, if present, occupies the section after the last "~".
+	// Without one, the token ends in "~" and that section is empty.
+	disclosureSections := sections[1:]
+	if last := sections[len(sections)-1]; last != "" {
+		kbJwt, err := parseKeyBindingJwt(last)
+		if err != nil {
+			return nil, err
+		}
+		sdJwt.kbJwt = kbJwt
+		disclosureSections = sections[1 : len(sections)-1]
+	}
+
+	tokenSections := strings.Split(sdJwt.token, ".")
 
 	if len(tokenSections) != 3 {
 		return nil, errors.New("token is not a valid JWT")
@@ -145,8 +166,9 @@ func validateJwt(token string) (*SdJwt, error) {
 	sdJwt.head = jwtHead
 
 	sdJwt.signature = tokenSections[2]
+	sdJwt.signingInput = tokenSections[0] + "." + tokenSections[1]
 
-	disclosures, err := validateDisclosures(sections[1:])
+	disclosures, err := validateDisclosures(disclosureSections)
 	if err != nil {
 		return nil, err
 	}
@@ -171,9 +193,13 @@ func validateJwt(token string) (*SdJwt, error) {
 	digests := getDigests(m)
 
 	for _, d := range digests {
+		digest, ok := d.(string)
+		if !ok {
+			continue
+		}
 		count := 0
-		for _, d2 := range sdJwt.disclosures {
-			if d == d2 {
+		for i := range sdJwt.disclosures {
+			if digest == disclosureDigest(&sdJwt.disclosures[i]) {
 				count++
 			}
 		}
@@ -192,45 +218,50 @@ func newDisclosure(d []byte) (*Disclosure, error) {
 	if err != nil {
 		return nil, err
 	}
-	if decodedDisclosure[0] != '[' || decodedDisclosure[len(decodedDisclosure)-1] != ']' {
-		return nil, errors.New("provided decoded disclosure is not a valid array")
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(decodedDisclosure, &parts); err != nil {
+		return nil, errors.New("provided decoded disclosure is not a valid JSON array")
 	}
 
 	disclosure := &Disclosure{}
-
-	parts := strings.Split(string(decodedDisclosure[1:len(decodedDisclosure)-1]), ",")
-
 	disclosure.setRawValue(string(decodedDisclosure))
 	disclosure.setEncodedValue(string(d))
-	if len(parts) == 2 {
-		disclosure.setSalt(*cleanStr(parts[0]))
-		disclosure.setClaimValue(*cleanStr(parts[1]))
-	} else {
-		parts[2] = strings.Join(parts[2:], ",")
-		parts = parts[:3]
 
-		if len(parts) != 3 {
-			return nil, errors.New("provided decoded disclosure does not have all required parts")
-		}
+	var salt string
 
-		disclosure.setSalt(*cleanStr(parts[0]))
-		disclosure.setClaimName(cleanStr(parts[1]))
-		disclosure.setClaimValue(*cleanStr(parts[2]))
+	switch len(parts) {
+	case 2:
+		if err := json.Unmarshal(parts[0], &salt); err != nil {
+			return nil, err
+		}
+		disclosure.setSalt(salt)
+		disclosure.setClaimValueJSON(parts[1])
+	case 3:
+		if err := json.Unmarshal(parts[0], &salt); err != nil {
+			return nil, err
+		}
+		var claimName string
+		if err := json.Unmarshal(parts[1], &claimName); err != nil {
+			return nil, err
+		}
+		disclosure.setSalt(salt)
+		disclosure.setClaimName(&claimName)
+		disclosure.setClaimValueJSON(parts[2])
+	default:
+		return nil, errors.New("provided decoded disclosure does not have all required parts")
 	}
-	return disclosure, nil
-}
 
-func cleanStr(s string) *string {
-	return Pointer(strings.TrimSpace(strings.Trim(strings.TrimSpace(s), "\"")))
+	return disclosure, nil
 }
 
+// validateDisclosures parses disclosures into Disclosure values, skipping
+// empty entries. An empty or nil disclosures is valid here: whether a
+// presentation is required to carry at least one "~" at all is the caller's
+// concern (see validateJwt), not whether it discloses anything.
 func validateDisclosures(disclosures []string) ([]Disclosure, error) {
 	var disclosureArray []Disclosure
 
-	if len(disclosures) == 0 {
-		return nil, errors.New("token has no specified disclosures")
-	}
-
 	for _, d := range disclosures {
 		count := 0
 		if d != "" {
@@ -276,179 +307,173 @@ func validateDigests(body map[string]interface{}) error {
 // 3. The SD-JWT contains an unsupported value for the _sd_alg claim
 // 4. The SD-JWT has a disclosure that is malformed for the use (e.g. doesn't contain a claim name for a non-array digest)
 func (s *SdJwt) GetDisclosedClaims() (map[string]any, error) {
-	bodyMap := make(map[string]any)
-
-	disclosuresToCheck := make([]Disclosure, len(s.disclosures))
-	copy(disclosuresToCheck, s.disclosures)
-	for len(disclosuresToCheck) > 0 {
-		d := disclosuresToCheck[0]
-
-		var h hash.Hash
-
-		switch s.body["_sd_alg"] {
-		case "none":
-			return nil, errors.New("none is not a valid algorithm")
-		case "sha-256":
-			h = sha256.New()
-		}
-
-		h.Write([]byte(d.EncodedValue()))
-		hashedDisclosures := h.Sum(nil)
-		base64HashedDisclosureBytes := make([]byte, base64.RawURLEncoding.EncodedLen(len(hashedDisclosures)))
-		base64.RawURLEncoding.Encode(base64HashedDisclosureBytes, hashedDisclosures)
-
-		found, err := validateSDClaims(s.Body(), &d, string(base64HashedDisclosureBytes))
-		if err != nil {
-			return nil, err
-		}
-
-		if !found {
-			return nil, errors.New("no matching digest found: " + d.RawValue() + " encoded: " + string(base64HashedDisclosureBytes))
-		}
+	switch alg, _ := s.body["_sd_alg"].(string); alg {
+	case "none":
+		return nil, errors.New("none is not a valid algorithm")
+	case "", "sha-256":
+		// sha-256 is the only supported algorithm, and is the specification's default.
+	default:
+		return nil, errors.New("unsupported _sd_alg: " + alg)
+	}
 
-		if len(disclosuresToCheck) > 1 {
-			disclosuresToCheck = disclosuresToCheck[1:]
-		} else {
-			disclosuresToCheck = []Disclosure{} //empty to-check array
-		}
+	byDigest := make(map[string]*Disclosure, len(s.disclosures))
+	for i := range s.disclosures {
+		byDigest[disclosureDigest(&s.disclosures[i])] = &s.disclosures[i]
+	}
 
+	bodyMap := make(map[string]any, len(s.body))
+	used := make(map[string]bool, len(byDigest))
+	if err := resolveSDClaims(s.body, bodyMap, byDigest, used); err != nil {
+		return nil, err
 	}
 
-	for k, v := range s.body {
-		if k != "_sd" && k != "_sd_alg" {
-			bodyMap[k] = v
+	for digest, d := range byDigest {
+		if !used[digest] {
+			return nil, errors.New("no matching digest found: " + d.RawValue() + " encoded: " + digest)
 		}
 	}
 
 	return bodyMap, nil
 }
 
-func getDigests(m map[string]any) []any {
-	var digests []any
-	for k, v := range m {
-		if reflect.TypeOf(v).Kind() == reflect.Map {
-			digests = append(digests, getDigests(v.(map[string]any))...)
-		} else if k == "_sd" {
-			digests = append(digests, v.([]any)...)
-		} else if reflect.TypeOf(v).Kind() == reflect.Slice {
-			for _, v2 := range v.([]any) {
-				b, err := json.Marshal(v2)
-				if err == nil {
-					var arrayDisclosure arrayDisclosure
-					err = json.Unmarshal(b, &arrayDisclosure)
-					if err == nil {
-						digests = append(digests, *arrayDisclosure.Digest)
-					}
-				}
-			}
-		}
-	}
-	return digests
+// disclosureDigest computes the base64url-encoded sha-256 digest of a
+// disclosure's encoded value, as found in an `_sd` array or a `{"...": ...}`
+// array element.
+func disclosureDigest(d *Disclosure) string {
+	h := sha256.Sum256([]byte(d.EncodedValue()))
+	return base64.RawURLEncoding.EncodeToString(h[:])
 }
 
-func parseClaimValue(cv string) (any, error) {
-	var m map[string]any
-	var s []any
-	var b bool
-	var i int
-
-	err := json.Unmarshal([]byte(cv), &m)
-	if err == nil {
-		return m, nil
-	}
-
-	err = json.Unmarshal([]byte(cv), &s)
-	if err == nil {
-		return s, nil
-	}
-
-	err = json.Unmarshal([]byte(cv), &b)
-	if err == nil {
-		return b, nil
+// resolveSDClaims substitutes every digest in src's "_sd" claim (if any)
+// into dst, keyed by the disclosed claim name, and recurses into nested
+// maps/slices to do the same at every level. src is left unchanged; dst
+// receives src's other claims alongside the disclosed ones.
+func resolveSDClaims(src map[string]any, dst map[string]any, byDigest map[string]*Disclosure, used map[string]bool) error {
+	if sd, ok := src["_sd"]; ok {
+		digests, ok := sd.([]any)
+		if !ok {
+			return errors.New("_sd claim is not an array")
+		}
+		for _, v := range digests {
+			digest, ok := v.(string)
+			if !ok {
+				return errors.New("_sd claim contains a non-string digest")
+			}
+			d, found := byDigest[digest]
+			if !found {
+				continue // no disclosure was provided for this digest (e.g. a decoy)
+			}
+			if d.ClaimName() == nil {
+				return errors.New("invalid disclosure format for _sd claim")
+			}
+			var val any
+			if err := json.Unmarshal(d.ClaimValueJSON(), &val); err != nil {
+				return err
+			}
+			dst[*d.ClaimName()] = val
+			used[digest] = true
+		}
 	}
 
-	err = json.Unmarshal([]byte(cv), &i)
-	if err == nil {
-		return i, nil
+	for k, v := range src {
+		if k == "_sd" || k == "_sd_alg" {
+			continue
+		}
+		resolved, err := resolveNestedValue(v, byDigest, used)
+		if err != nil {
+			return err
+		}
+		dst[k] = resolved
 	}
 
-	//Return string as a fallback
-	return cv, nil
+	return nil
 }
 
-func validateSDClaims(values *map[string]any, currentDisclosure *Disclosure, base64HashedDisclosure string) (found bool, err error) {
-	if _, ok := (*values)["_sd"]; ok {
-		for _, digest := range (*values)["_sd"].([]any) {
-			if digest == base64HashedDisclosure {
-				if currentDisclosure.ClaimName() != nil {
-					val, err := parseClaimValue(currentDisclosure.ClaimValue())
-					if err != nil {
-						return false, err
+// resolveNestedValue resolves digests within v, a value found inside the
+// SD-JWT body, returning the value that should replace it in the disclosed
+// claims.
+func resolveNestedValue(v any, byDigest map[string]*Disclosure, used map[string]bool) (any, error) {
+	switch val := v.(type) {
+	case map[string]any:
+		resolved := make(map[string]any, len(val))
+		if err := resolveSDClaims(val, resolved, byDigest, used); err != nil {
+			return nil, err
+		}
+		return resolved, nil
+	case []any:
+		return resolveArrayClaims(val, byDigest, used)
+	default:
+		return v, nil
+	}
+}
+
+// resolveArrayClaims resolves digests within an array claim, substituting
+// each `{"...": "<digest>"}` element with its disclosed value in place.
+func resolveArrayClaims(s []any, byDigest map[string]*Disclosure, used map[string]bool) ([]any, error) {
+	resolved := make([]any, len(s))
+	for i, v := range s {
+		if m, ok := v.(map[string]any); ok {
+			if digest, ok := m["..."].(string); ok && len(m) == 1 {
+				if d, found := byDigest[digest]; found {
+					var val any
+					if err := json.Unmarshal(d.ClaimValueJSON(), &val); err != nil {
+						return nil, err
 					}
-					(*values)[*currentDisclosure.ClaimName()] = val
-					return true, nil
-				} else {
-					return false, errors.New("invalid disclosure format for _sd claim")
+					resolved[i] = val
+					used[digest] = true
+					continue
 				}
+				resolved[i] = v // no disclosure was provided for this digest (e.g. a decoy)
+				continue
 			}
 		}
-	}
 
-	for k, v := range *values {
-		if k != "_sd" && k != "_sd_alg" {
-			if reflect.TypeOf(v).Kind() == reflect.Slice {
-				found, err = validateArrayClaims(PointerSlice(v.([]any)), currentDisclosure, base64HashedDisclosure)
-				if err != nil {
-					return false, err
-				}
-			} else if reflect.TypeOf(v).Kind() == reflect.Map {
-				found, err = validateSDClaims(PointerMap(v.(map[string]any)), currentDisclosure, base64HashedDisclosure)
-				if err != nil {
-					return found, err
-				}
-			}
-			if found {
-				return true, nil
-			}
+		val, err := resolveNestedValue(v, byDigest, used)
+		if err != nil {
+			return nil, err
 		}
+		resolved[i] = val
 	}
-	return false, nil
+	return resolved, nil
 }
 
-func validateArrayClaims(s *[]any, currentDisclosure *Disclosure, base64HashedDisclosure string) (found bool, err error) {
-
-	for i, v := range *s {
-		ad := &arrayDisclosure{}
-		vb, err := json.Marshal(v)
-		if err != nil {
-			return false, err
-		}
-
-		_ = json.Unmarshal(vb, ad)
-
-		if ad.Digest != nil {
-			if *ad.Digest == base64HashedDisclosure {
-				(*s)[i] = currentDisclosure.ClaimValue()
-				return true, nil
-			}
-		}
-
-		if reflect.TypeOf(v).Kind() == reflect.Slice {
-			found, err = validateArrayClaims(PointerSlice(v.([]any)), currentDisclosure, base64HashedDisclosure)
-			if err != nil {
-				return found, err
+// getDigests returns every disclosure digest present in m, from its `_sd`
+// arrays and its array element disclosures, at any depth.
+func getDigests(m map[string]any) []any {
+	var digests []any
+	for k, v := range m {
+		switch val := v.(type) {
+		case map[string]any:
+			digests = append(digests, getDigests(val)...)
+		case []any:
+			if k == "_sd" {
+				digests = append(digests, val...)
+			} else {
+				digests = append(digests, getArrayDigests(val)...)
 			}
 		}
+	}
+	return digests
+}
 
-		if reflect.TypeOf(v).Kind() == reflect.Map {
-			found, err = validateSDClaims(PointerMap(v.(map[string]any)), currentDisclosure, base64HashedDisclosure)
-			if err != nil {
-				return found, err
+// getArrayDigests returns every disclosure digest present in an array claim,
+// from its `{"...": "<digest>"}` elements and any nested `_sd`/array element
+// disclosures.
+func getArrayDigests(s []any) []any {
+	var digests []any
+	for _, v := range s {
+		switch val := v.(type) {
+		case map[string]any:
+			if digest, ok := val["..."]; ok && len(val) == 1 {
+				digests = append(digests, digest)
+			} else {
+				digests = append(digests, getDigests(val)...)
 			}
+		case []any:
+			digests = append(digests, getArrayDigests(val)...)
 		}
 	}
-
-	return false, nil
+	return digests
 }
 
 // Body returns the body of the JWT
@@ -466,6 +491,12 @@ func (s *SdJwt) Signature() string {
 	return s.signature
 }
 
+// SigningInput returns the raw "<header>.<payload>" string the signature was
+// computed over, as required to verify it.
+func (s *SdJwt) SigningInput() string {
+	return s.signingInput
+}
+
 // Head returns the head of the JWT
 func (s *SdJwt) Head() map[string]any {
 	return s.head
@@ -476,14 +507,34 @@ func (s *SdJwt) Disclosures() []Disclosure {
 	return s.disclosures
 }
 
+// KeyBindingJwt returns the Key Binding JWT presented alongside this SD-JWT,
+// or nil if none was present.
+func (s *SdJwt) KeyBindingJwt() *KeyBindingJwt {
+	return s.kbJwt
+}
+
 // ClaimName returns the claim name of the disclosure
 func (d *Disclosure) ClaimName() *string {
 	return d.claimName
 }
 
-// ClaimValue returns the claim value of the disclosure
+// ClaimValue returns the claim value of the disclosure as a string. For a
+// JSON string value this is the unquoted string itself; for any other JSON
+// type (object, array, number, bool) it is that value's raw JSON text.
+// Prefer ClaimValueJSON when the claim value is not known to be a string.
 func (d *Disclosure) ClaimValue() string {
-	return d.claimValue
+	var s string
+	if json.Unmarshal(d.claimValueJSON, &s) == nil {
+		return s
+	}
+	return string(d.claimValueJSON)
+}
+
+// ClaimValueJSON returns the claim value exactly as it appeared in the
+// decoded disclosure, i.e. verbatim, byte for byte as it appears in the
+// disclosure the digest was computed over.
+func (d *Disclosure) ClaimValueJSON() json.RawMessage {
+	return d.claimValueJSON
 }
 
 // Salt returns the salt of the disclosure
@@ -505,8 +556,8 @@ func (d *Disclosure) setClaimName(claimName *string) {
 	d.claimName = claimName
 }
 
-func (d *Disclosure) setClaimValue(claimValue string) {
-	d.claimValue = claimValue
+func (d *Disclosure) setClaimValueJSON(claimValue json.RawMessage) {
+	d.claimValueJSON = claimValue
 }
 
 func (d *Disclosure) setSalt(salt string) {
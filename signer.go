@@ -0,0 +1,93 @@
+package go_sd_jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+)
+
+// Signer is implemented by anything capable of producing a JWS signature over
+// a JWT signing input (the base64url-encoded "<header>.<payload>"). This is
+// the extension point that lets callers plug in keys held outside the process,
+// e.g. an HSM or a cloud KMS, without this package ever seeing the private key.
+type Signer interface {
+	// Algorithm returns the JWA "alg" identifier this Signer produces, e.g. "RS256".
+	Algorithm() string
+	// Sign returns the raw signature bytes over signingInput.
+	Sign(signingInput []byte) ([]byte, error)
+}
+
+// RS256Signer signs using RSASSA-PKCS1-v1_5 with SHA-256.
+type RS256Signer struct {
+	PrivateKey *rsa.PrivateKey
+}
+
+// Algorithm returns "RS256".
+func (s *RS256Signer) Algorithm() string {
+	return "RS256"
+}
+
+// Sign produces an RS256 signature over signingInput.
+func (s *RS256Signer) Sign(signingInput []byte) ([]byte, error) {
+	if s.PrivateKey == nil {
+		return nil, errors.New("no private key provided to RS256Signer")
+	}
+	hashed := sha256.Sum256(signingInput)
+	return rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, hashed[:])
+}
+
+// ES256Signer signs using ECDSA over the P-256 curve with SHA-256.
+type ES256Signer struct {
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// Algorithm returns "ES256".
+func (s *ES256Signer) Algorithm() string {
+	return "ES256"
+}
+
+// Sign produces an ES256 signature over signingInput, encoded as the
+// fixed-length R||S concatenation required by JWS (not ASN.1 DER).
+func (s *ES256Signer) Sign(signingInput []byte) ([]byte, error) {
+	if s.PrivateKey == nil {
+		return nil, errors.New("no private key provided to ES256Signer")
+	}
+	hashed := sha256.Sum256(signingInput)
+	return signEcdsa(s.PrivateKey, hashed[:], 32)
+}
+
+// EdDSASigner signs using Ed25519 as defined for JWA "EdDSA".
+type EdDSASigner struct {
+	PrivateKey ed25519.PrivateKey
+}
+
+// Algorithm returns "EdDSA".
+func (s *EdDSASigner) Algorithm() string {
+	return "EdDSA"
+}
+
+// Sign produces an EdDSA signature over signingInput.
+func (s *EdDSASigner) Sign(signingInput []byte) ([]byte, error) {
+	if s.PrivateKey == nil {
+		return nil, errors.New("no private key provided to EdDSASigner")
+	}
+	return ed25519.Sign(s.PrivateKey, signingInput), nil
+}
+
+// signEcdsa signs a digest and returns the JWS fixed-length R||S encoding,
+// where each of R and S is left-padded with zeros to keyByteLen bytes.
+func signEcdsa(key *ecdsa.PrivateKey, digest []byte, keyByteLen int) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 2*keyByteLen)
+	r.FillBytes(out[:keyByteLen])
+	s.FillBytes(out[keyByteLen:])
+	return out, nil
+}
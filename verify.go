@@ -0,0 +1,102 @@
+package go_sd_jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"math/big"
+)
+
+// Verify resolves the key that signed this SD-JWT via keyResolver and checks
+// its signature. It dispatches on the "alg" (and, when present, "kid") found
+// in Head(). A nil error means the signature is valid for the signing input
+// returned by SigningInput.
+func (s *SdJwt) Verify(keyResolver KeyResolver) error {
+	if keyResolver == nil {
+		return errors.New("no key resolver provided")
+	}
+
+	alg, _ := s.head["alg"].(string)
+	if alg == "" {
+		return errors.New("SD-JWT header has no alg")
+	}
+	kid, _ := s.head["kid"].(string)
+
+	key, err := keyResolver.ResolveKey(kid, alg)
+	if err != nil {
+		return err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(s.signature)
+	if err != nil {
+		return err
+	}
+
+	return verifySignature(alg, key, []byte(s.signingInput), sig)
+}
+
+// verifySignature checks sig over signingInput using key, per the semantics
+// of the given JWA alg identifier.
+func verifySignature(alg string, key crypto.PublicKey, signingInput []byte, sig []byte) error {
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("RS256 requires an RSA public key")
+		}
+		hashed := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], sig)
+	case "PS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("PS256 requires an RSA public key")
+		}
+		hashed := sha256.Sum256(signingInput)
+		return rsa.VerifyPSS(pub, crypto.SHA256, hashed[:], sig, nil)
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("ES256 requires an ECDSA public key")
+		}
+		hashed := sha256.Sum256(signingInput)
+		return verifyEcdsa(pub, hashed[:], sig, 32)
+	case "ES384":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("ES384 requires an ECDSA public key")
+		}
+		hashed := sha512.Sum384(signingInput)
+		return verifyEcdsa(pub, hashed[:], sig, 48)
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("EdDSA requires an Ed25519 public key")
+		}
+		if !ed25519.Verify(pub, signingInput, sig) {
+			return errors.New("EdDSA signature verification failed")
+		}
+		return nil
+	default:
+		return errors.New("unsupported alg: " + alg)
+	}
+}
+
+// verifyEcdsa checks a JWS-encoded (fixed-length R||S) ECDSA signature.
+func verifyEcdsa(pub *ecdsa.PublicKey, digest []byte, sig []byte, keyByteLen int) error {
+	if len(sig) != 2*keyByteLen {
+		return errors.New("ECDSA signature has unexpected length")
+	}
+
+	r := new(big.Int).SetBytes(sig[:keyByteLen])
+	s := new(big.Int).SetBytes(sig[keyByteLen:])
+
+	if !ecdsa.Verify(pub, digest, r, s) {
+		return errors.New("ECDSA signature verification failed")
+	}
+	return nil
+}
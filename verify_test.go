@@ -0,0 +1,161 @@
+package go_sd_jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+)
+
+// ps256Signer signs using RSASSA-PSS with SHA-256, for exercising Verify's
+// PS256 branch. RS256Signer is the only RSA signer this package exports, so
+// the test builds its own minimal Signer here rather than adding a product
+// type nobody asked for.
+type ps256Signer struct {
+	privateKey *rsa.PrivateKey
+}
+
+func (s *ps256Signer) Algorithm() string { return "PS256" }
+
+func (s *ps256Signer) Sign(signingInput []byte) ([]byte, error) {
+	hashed := sha256.Sum256(signingInput)
+	return rsa.SignPSS(rand.Reader, s.privateKey, crypto.SHA256, hashed[:], nil)
+}
+
+// es384Signer signs using ECDSA over the P-384 curve with SHA-384, for
+// exercising Verify's ES384 branch.
+type es384Signer struct {
+	privateKey *ecdsa.PrivateKey
+}
+
+func (s *es384Signer) Algorithm() string { return "ES384" }
+
+func (s *es384Signer) Sign(signingInput []byte) ([]byte, error) {
+	hashed := sha512.Sum384(signingInput)
+	return signEcdsa(s.privateKey, hashed[:], 48)
+}
+
+// TestVerifyRoundTrip signs a minimal SD-JWT with each supported algorithm
+// and checks that Verify accepts it against the matching public key, rejects
+// it against a different key (for every algorithm but EdDSA), and rejects a
+// tampered signature.
+func TestVerifyRoundTrip(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	es256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate P-256 key: %v", err)
+	}
+	es384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate P-384 key: %v", err)
+	}
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate Ed25519 key: %v", err)
+	}
+
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		signer    Signer
+		publicKey any
+		wrongKey  any
+	}{
+		{"RS256", &RS256Signer{PrivateKey: rsaKey}, &rsaKey.PublicKey, &otherRSAKey.PublicKey},
+		{"PS256", &ps256Signer{privateKey: rsaKey}, &rsaKey.PublicKey, &otherRSAKey.PublicKey},
+		{"ES256", &ES256Signer{PrivateKey: es256Key}, &es256Key.PublicKey, &es384Key.PublicKey},
+		{"ES384", &es384Signer{privateKey: es384Key}, &es384Key.PublicKey, &es256Key.PublicKey},
+		{"EdDSA", &EdDSASigner{PrivateKey: edPriv}, edPub, edPub},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, err := NewIssuer(tt.signer).AddAlwaysVisible("iss", "https://example.com").Sign()
+			if err != nil {
+				t.Fatalf("failed to sign: %v", err)
+			}
+
+			sdJwt, err := New(token)
+			if err != nil {
+				t.Fatalf("failed to parse issued token: %v", err)
+			}
+
+			if err := sdJwt.Verify(&StaticKeyResolver{Key: tt.publicKey}); err != nil {
+				t.Errorf("expected valid signature to verify, got: %v", err)
+			}
+
+			if tt.name != "EdDSA" {
+				if err := sdJwt.Verify(&StaticKeyResolver{Key: tt.wrongKey}); err == nil {
+					t.Errorf("expected verification against the wrong key to fail")
+				}
+			}
+
+			tampered, err := New(token[:len(token)-2] + "XX~")
+			if err != nil {
+				t.Fatalf("failed to parse tampered token: %v", err)
+			}
+			if err := tampered.Verify(&StaticKeyResolver{Key: tt.publicKey}); err == nil {
+				t.Errorf("expected tampered signature to fail verification")
+			}
+		})
+	}
+}
+
+// TestVerifyUnsupportedAlg checks that Verify surfaces an error for an alg
+// the resolver can resolve a key for but verifySignature doesn't implement.
+func TestVerifyUnsupportedAlg(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	token, _, err := NewIssuer(&EdDSASigner{PrivateKey: priv}).AddAlwaysVisible("iss", "x").Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+	sdJwt.head["alg"] = "HS256"
+
+	if err := sdJwt.Verify(&StaticKeyResolver{Key: priv.Public()}); err == nil {
+		t.Errorf("expected unsupported alg to return an error")
+	}
+}
+
+// TestVerifyNoKeyResolver checks that Verify rejects a nil KeyResolver
+// instead of panicking.
+func TestVerifyNoKeyResolver(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	token, _, err := NewIssuer(&EdDSASigner{PrivateKey: priv}).AddAlwaysVisible("iss", "x").Sign()
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	sdJwt, err := New(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+
+	if err := sdJwt.Verify(nil); err == nil {
+		t.Errorf("expected nil KeyResolver to return an error")
+	}
+}